@@ -0,0 +1,90 @@
+package rich_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/awfm/rich"
+	rlogrus "github.com/awfm/rich/logrus"
+	rslog "github.com/awfm/rich/slog"
+	rzap "github.com/awfm/rich/zap"
+	rzerolog "github.com/awfm/rich/zerolog"
+	"go.uber.org/zap"
+)
+
+func TestAdapterChains(t *testing.T) {
+	_, base := os.Open("/nonexistent")
+	adapters := []struct {
+		name string
+		err  error
+		wrap func(error, string) error
+	}{
+		{"root", rich.Errorf("op: %w", base).Str("k", "v").Int("n", 1).Code(rich.NotFound), rich.Wrap},
+		{"zerolog", rzerolog.Errorf("op: %w", base).Str("k", "v").Int("n", 1).Code(rich.NotFound), rzerolog.Wrap},
+		{"zap", rzap.Errorf("op: %w", base).With(zap.String("k", "v"), zap.Int("n", 1)).Code(rich.NotFound), rzap.Wrap},
+		{"sugared", rzap.Errorf("op: %w", base).Sugar().With("k", "v", "n", 1).Code(rich.NotFound), rzap.Wrap},
+		{"logrus", rlogrus.Errorf("op: %w", base).WithField("k", "v").WithField("n", 1).WithCode(rich.NotFound), rlogrus.Wrap},
+		{"slog", rslog.Errorf("op: %w", base).String("k", "v").Int("n", 1).Code(rich.NotFound), rslog.Wrap},
+	}
+	for _, a := range adapters {
+		chains := []struct {
+			name string
+			err  error
+		}{
+			{"direct", a.err},
+			{"fmt", fmt.Errorf("outer: %w", a.err)},
+			{"wrap", a.wrap(a.err, "outer")},
+			{"join", errors.Join(a.err, errors.New("other"))},
+		}
+		for _, c := range chains {
+			t.Run(a.name+"/"+c.name, func(t *testing.T) {
+				err := c.err
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Error("errors.Is(os.ErrNotExist) = false")
+				}
+				if !errors.Is(err, a.err) {
+					t.Error("errors.Is(original) = false")
+				}
+				var pe *fs.PathError
+				if !errors.As(err, &pe) || pe.Path != "/nonexistent" {
+					t.Errorf("errors.As(*fs.PathError) = %v", pe)
+				}
+				var re *rich.Error
+				if !errors.As(err, &re) {
+					t.Error("errors.As(*rich.Error) = false")
+				}
+				fields := rich.Fields(err)
+				inner := err
+				if c.name == "join" {
+					bs, ok := rich.Lookup[[]rich.Branch](err, rich.ErrorsFieldName)
+					if !ok || len(bs) != 2 {
+						t.Fatalf("Fields = %v, want two branches", fields)
+					}
+					fields = bs[0].Fields
+					inner = bs[0].Err
+				}
+				want := []rich.Field{{Key: "k", Val: "v"}, {Key: "n", Val: 1}, {Key: rich.CodeFieldName, Val: rich.NotFound}}
+				if len(fields) != len(want) {
+					t.Fatalf("Fields = %v, want %v", fields, want)
+				}
+				for i, f := range fields {
+					if f.Key != want[i].Key || fmt.Sprint(f.Val) != fmt.Sprint(want[i].Val) {
+						t.Errorf("Fields[%d] = %v, want %v", i, f, want[i])
+					}
+				}
+				if n, ok := rich.Lookup[int](inner, "n"); !ok || n != 1 {
+					t.Errorf("Lookup[int] = %v, %v, want 1, true", n, ok)
+				}
+				if n, ok := rich.Lookup[int64](inner, "n"); !ok || n != 1 {
+					t.Errorf("Lookup[int64] = %v, %v, want 1, true", n, ok)
+				}
+				if code := rich.CodeOf(inner); code != rich.NotFound {
+					t.Errorf("CodeOf = %q, want %q", code, rich.NotFound)
+				}
+			})
+		}
+	}
+}
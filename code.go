@@ -0,0 +1,82 @@
+package rich
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type Code string
+
+const (
+	Unknown            Code = "unknown"
+	InvalidArgument    Code = "invalid_argument"
+	NotFound           Code = "not_found"
+	AlreadyExists      Code = "already_exists"
+	Conflict           Code = "conflict"
+	PermissionDenied   Code = "permission_denied"
+	Unauthenticated    Code = "unauthenticated"
+	FailedPrecondition Code = "failed_precondition"
+	ResourceExhausted  Code = "resource_exhausted"
+	Canceled           Code = "canceled"
+	DeadlineExceeded   Code = "deadline_exceeded"
+	Unimplemented      Code = "unimplemented"
+	Unavailable        Code = "unavailable"
+	Internal           Code = "internal"
+)
+
+var HTTPStatuses = map[Code]int{
+	Unknown:            http.StatusInternalServerError,
+	InvalidArgument:    http.StatusBadRequest,
+	NotFound:           http.StatusNotFound,
+	AlreadyExists:      http.StatusConflict,
+	Conflict:           http.StatusConflict,
+	PermissionDenied:   http.StatusForbidden,
+	Unauthenticated:    http.StatusUnauthorized,
+	FailedPrecondition: http.StatusBadRequest,
+	ResourceExhausted:  http.StatusTooManyRequests,
+	Canceled:           499,
+	DeadlineExceeded:   http.StatusGatewayTimeout,
+	Unimplemented:      http.StatusNotImplemented,
+	Unavailable:        http.StatusServiceUnavailable,
+	Internal:           http.StatusInternalServerError,
+}
+
+func (c Code) String() string {
+	return string(c)
+}
+
+func (e *Error) Code(c Code) *Error {
+	return e.with(Field{Key: CodeFieldName, Val: c})
+}
+
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	if c, ok := Lookup[Code](err, CodeFieldName); ok {
+		return c
+	}
+	var coder interface{ ErrorCode() Code }
+	if errors.As(err, &coder) {
+		return coder.ErrorCode()
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	}
+	return Unknown
+}
+
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	status, ok := HTTPStatuses[CodeOf(err)]
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return status
+}
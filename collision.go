@@ -0,0 +1,92 @@
+package rich
+
+import (
+	"strconv"
+)
+
+type Policy int
+
+const (
+	LastWins Policy = iota
+	FirstWins
+	PrefixDepth
+	Collect
+)
+
+var (
+	Collisions          = LastWins
+	Debug               = false
+	CollisionsFieldName = "collisions"
+)
+
+func Merge(err error, caller ...Field) []Field {
+	return Resolve(append(Sources(err), caller)...)
+}
+
+func Sources(err error) [][]Field {
+	ls := Chain(err)
+	sources := make([][]Field, 0, len(ls))
+	for i := len(ls) - 1; i >= 0; i-- {
+		sources = append(sources, ls[i].Fields)
+	}
+	return sources
+}
+
+func Resolve(sources ...[]Field) []Field {
+	count := make(map[string]int)
+	var keys []string
+	for _, source := range sources {
+		for _, f := range source {
+			if f.Key == "" {
+				continue
+			}
+			count[f.Key]++
+			if count[f.Key] == 2 {
+				keys = append(keys, f.Key)
+			}
+		}
+	}
+	var fs []Field
+	seen := make(map[string]int)
+	collected := make(map[string]int)
+	for i, source := range sources {
+		depth := len(sources) - 1 - i
+		for _, f := range source {
+			n := count[f.Key]
+			if n < 2 {
+				fs = append(fs, f)
+				continue
+			}
+			seen[f.Key]++
+			switch Collisions {
+			case FirstWins:
+				if seen[f.Key] == 1 {
+					fs = append(fs, f)
+				}
+			case PrefixDepth:
+				if seen[f.Key] < n {
+					f.Key = strconv.Itoa(depth) + "." + f.Key
+				}
+				fs = append(fs, f)
+			case Collect:
+				if seen[f.Key] == 1 {
+					collected[f.Key] = len(fs)
+					fs = append(fs, Field{Key: f.Key, Val: []interface{}{f.Val}, Secret: f.Secret, Public: f.Public})
+					continue
+				}
+				c := &fs[collected[f.Key]]
+				c.Val = append(c.Val.([]interface{}), f.Val)
+				c.Secret = c.Secret || f.Secret
+				c.Public = c.Public && f.Public
+			default:
+				if seen[f.Key] == n {
+					fs = append(fs, f)
+				}
+			}
+		}
+	}
+	if Debug && len(keys) > 0 {
+		fs = append([]Field{{Key: CollisionsFieldName, Val: keys}}, fs...)
+	}
+	return fs
+}
@@ -0,0 +1,60 @@
+package rich
+
+import (
+	"context"
+	"fmt"
+)
+
+type contextKey struct{}
+
+func ContextWith(ctx context.Context, fs ...Field) context.Context {
+	prev := ContextFields(ctx)
+	return context.WithValue(ctx, contextKey{}, append(prev[:len(prev):len(prev)], fs...))
+}
+
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fs, _ := ctx.Value(contextKey{}).([]Field)
+	return fs
+}
+
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) *Error {
+	e := &Error{
+		err: fmt.Errorf(format, a...),
+		fs:  ContextFields(ctx),
+	}
+	if CaptureStack {
+		e.stack = callers(0)
+	}
+	return e
+}
+
+func MergeContext(ctx context.Context, err error, caller ...Field) []Field {
+	return ResolveContext(ctx, append(Sources(err), caller)...)
+}
+
+func ResolveContext(ctx context.Context, sources ...[]Field) []Field {
+	fs := ContextFields(ctx)
+	if len(fs) == 0 {
+		return Resolve(sources...)
+	}
+	if len(sources) == 0 {
+		sources = [][]Field{nil}
+	}
+	set := make(map[string]bool)
+	for _, source := range sources[:len(sources)-1] {
+		for _, f := range source {
+			set[f.Key] = true
+		}
+	}
+	caller := make([]Field, 0, len(fs)+len(sources[len(sources)-1]))
+	for _, f := range fs {
+		if !set[f.Key] {
+			caller = append(caller, f)
+		}
+	}
+	caller = append(caller, sources[len(sources)-1]...)
+	return Resolve(append(sources[:len(sources)-1:len(sources)-1], caller)...)
+}
@@ -0,0 +1,276 @@
+package rich
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+type Error struct {
+	err   error
+	fs    fields
+	stack Stack
+}
+
+func Errorf(format string, a ...interface{}) *Error {
+	e := &Error{
+		err: fmt.Errorf(format, a...),
+	}
+	if CaptureStack {
+		e.stack = callers(0)
+	}
+	return e
+}
+
+func New(msg string) *Error {
+	e := &Error{
+		err: errors.New(msg),
+	}
+	if CaptureStack {
+		e.stack = callers(0)
+	}
+	return e
+}
+
+func Wrap(err error, msg string) error {
+	return WrapSkip(err, msg, 0)
+}
+
+func WrapSkip(err error, msg string, skip int) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{
+		err: &wrapper{msg + ": " + fmt.Sprint(err), err},
+	}
+	if CaptureStack {
+		e.stack = callers(skip)
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	fs := e.fs.String()
+	if fs == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s (%s)", e.err, fs)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+type wrapper struct {
+	msg string
+	err error
+}
+
+func (w *wrapper) Error() string {
+	return w.msg
+}
+
+func (w *wrapper) Unwrap() error {
+	return w.err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		t, ok = errors.Unwrap(target).(*Error)
+		ok = ok && t.Error() == target.Error()
+	}
+	return ok && t.err == e.err
+}
+
+func Strip(err error) error {
+	if r, ok := err.(*Error); ok {
+		return r.err
+	}
+	r, ok := errors.Unwrap(err).(*Error)
+	if ok && r.Error() == err.Error() {
+		return r.err
+	}
+	return err
+}
+
+func (e *Error) Fields() []Field {
+	return append([]Field(nil), e.fs...)
+}
+
+func (e *Error) WithStack() *Error {
+	return &Error{
+		err:   e.err,
+		fs:    e.fs,
+		stack: callers(0),
+	}
+}
+
+func (e *Error) With(fs ...Field) *Error {
+	return e.with(fs...)
+}
+
+func (e *Error) with(fs ...Field) *Error {
+	return &Error{
+		err:   e.err,
+		fs:    append(e.fs[:len(e.fs):len(e.fs)], fs...),
+		stack: e.stack,
+	}
+}
+
+func (e *Error) Bool(key string, val bool) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Int(key string, val int) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Int8(key string, val int8) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Int16(key string, val int16) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Int32(key string, val int32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Int64(key string, val int64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uint(key string, val uint) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uint8(key string, val uint8) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uint16(key string, val uint16) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uint32(key string, val uint32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uint64(key string, val uint64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Float32(key string, val float32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Float64(key string, val float64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Str(key string, val string) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) AnErr(key string, val error) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Dur(key string, val time.Duration) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Time(key string, val time.Time) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Bools(key string, val []bool) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Ints(key string, val []int) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Ints8(key string, val []int8) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Ints16(key string, val []int16) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Ints32(key string, val []int32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Ints64(key string, val []int64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uints(key string, val []uint) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uints8(key string, val []uint8) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uints16(key string, val []uint16) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uints32(key string, val []uint32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Uints64(key string, val []uint64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Floats32(key string, val []float32) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Floats64(key string, val []float64) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Strs(key string, val []string) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Errs(key string, val []error) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Durs(key string, val []time.Duration) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Times(key string, val []time.Time) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) IPAddr(key string, val net.IP) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) IPPrefix(key string, val net.IPNet) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) MACAddr(key string, val net.HardwareAddr) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Interface(key string, val interface{}) *Error {
+	return e.with(Field{Key: key, Val: val})
+}
+
+func (e *Error) Secret(key string, val interface{}) *Error {
+	return e.with(Field{Key: key, Val: val, Secret: true})
+}
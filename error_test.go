@@ -0,0 +1,103 @@
+package rich_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/awfm/rich"
+)
+
+func TestBuildersShareNothing(t *testing.T) {
+	sentinel := rich.New("sentinel").Str("base", "value")
+	const n = 64
+	errs := make([]error, 3*n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "k" + strconv.Itoa(i)
+			errs[3*i] = sentinel.Str(key, key)
+			errs[3*i+1] = rich.Errorf("wrap: %w", sentinel).Int(key, i)
+			errs[3*i+2] = sentinel.Str(key, key).Public(key, "base")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		key := "k" + strconv.Itoa(i/3)
+		fs := rich.Fields(err)
+		want := map[string]bool{"base": true, key: true}
+		if len(fs) != len(want) {
+			t.Fatalf("error %d: got fields %v, want keys base and %s", i, fs, key)
+		}
+		for _, f := range fs {
+			if !want[f.Key] {
+				t.Fatalf("error %d: unexpected field %v", i, f)
+			}
+		}
+		if i%3 == 2 {
+			for _, f := range fs {
+				if !f.Public {
+					t.Fatalf("error %d: field %s should be public", i, f.Key)
+				}
+			}
+		}
+	}
+
+	fs := rich.Fields(sentinel)
+	if len(fs) != 1 || fs[0].Key != "base" || fs[0].Public {
+		t.Fatalf("sentinel changed: %v", fs)
+	}
+}
+
+func TestReturnedFieldsAreCopies(t *testing.T) {
+	sentinel := rich.New("x").Str("k", "v")
+	sentinel.Fields()[0].Val = "mutated"
+	rich.Chain(sentinel)[0].Fields[0].Val = "mutated"
+	rich.Fields(sentinel)[0].Val = "mutated"
+	if got := sentinel.Error(); got != "x (k: v)" {
+		t.Fatalf("sentinel changed: %q", got)
+	}
+}
+
+func TestRecoverKeepsStackOutOfMessage(t *testing.T) {
+	err := func() (err error) {
+		defer rich.Recover(&err)
+		panic("boom")
+	}()
+	if got, want := err.Error(), "panic: boom (error_code: internal, panic_value: boom, panic_type: string)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	st := rich.StackTrace(err)
+	if len(st) == 0 || !strings.Contains(st.String(), "TestRecoverKeepsStackOutOfMessage") {
+		t.Fatalf("stack does not reach the panicking function:\n%s", st)
+	}
+}
+
+func TestLookupConvertsLosslessly(t *testing.T) {
+	err := rich.New("e").Int64("big", 1<<40).Int("neg", -1).Float64("half", 0.5).Float64("tenth", 0.1).Dur("d", 1)
+	if _, ok := rich.Lookup[int32](err, "big"); ok {
+		t.Error("Lookup[int32] of 1<<40 succeeded")
+	}
+	if _, ok := rich.Lookup[uint](err, "neg"); ok {
+		t.Error("Lookup[uint] of -1 succeeded")
+	}
+	if n, ok := rich.Lookup[int8](err, "neg"); !ok || n != -1 {
+		t.Errorf("Lookup[int8] = %v, %v, want -1, true", n, ok)
+	}
+	if f, ok := rich.Lookup[float32](err, "half"); !ok || f != 0.5 {
+		t.Errorf("Lookup[float32] = %v, %v, want 0.5, true", f, ok)
+	}
+	if _, ok := rich.Lookup[float32](err, "tenth"); ok {
+		t.Error("Lookup[float32] of 0.1 succeeded")
+	}
+	if _, ok := rich.Lookup[int](err, "half"); ok {
+		t.Error("Lookup[int] of 0.5 succeeded")
+	}
+	if _, ok := rich.Lookup[int64](err, "d"); ok {
+		t.Error("Lookup[int64] of a time.Duration succeeded")
+	}
+}
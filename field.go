@@ -0,0 +1,174 @@
+package rich
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type Field struct {
+	Key    string
+	Val    interface{}
+	Secret bool
+	Public bool
+}
+
+func (f Field) String() string {
+	f, ok := f.Redact(ErrorRedaction)
+	if !ok {
+		f.Val = MaskText
+	}
+	return fmt.Sprintf("%s: %v", f.Key, f.Val)
+}
+
+type fields []Field
+
+func (fs fields) String() string {
+	ss := make([]string, 0, len(fs))
+	for _, f := range fs {
+		f, ok := f.Redact(ErrorRedaction)
+		if !ok {
+			continue
+		}
+		ss = append(ss, fmt.Sprintf("%s: %v", f.Key, f.Val))
+	}
+	return strings.Join(ss, ", ")
+}
+
+var (
+	ErrorsFieldName = "errors"
+	ChainFieldName  = "error_chain"
+	CodeFieldName   = "error_code"
+)
+
+type Branch struct {
+	Err    error
+	Fields []Field
+}
+
+type Layer struct {
+	Err    error
+	Fields []Field
+}
+
+func Fields(err error) []Field {
+	var layers [][]Field
+	for err != nil {
+		r, ok := err.(*Error)
+		if ok && len(r.fs) > 0 {
+			layers = append(layers, r.fs)
+		}
+		m, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			err = errors.Unwrap(err)
+			continue
+		}
+		bs, ok := branches(m.Unwrap())
+		if ok {
+			layers = append(layers, []Field{{Key: ErrorsFieldName, Val: bs}})
+		}
+		break
+	}
+	var fs []Field
+	for i := len(layers) - 1; i >= 0; i-- {
+		fs = append(fs, layers[i]...)
+	}
+	return fs
+}
+
+func Chain(err error) []Layer {
+	var layers []Layer
+	for err != nil {
+		r, ok := err.(*Error)
+		if ok {
+			layers = append(layers, Layer{r, append([]Field(nil), r.fs...)})
+		}
+		m, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			err = errors.Unwrap(err)
+			continue
+		}
+		bs, ok := branches(m.Unwrap())
+		if !ok {
+			break
+		}
+		if len(layers) == 0 {
+			layers = append(layers, Layer{Err: err})
+		}
+		last := &layers[len(layers)-1]
+		last.Fields = append(last.Fields[:len(last.Fields):len(last.Fields)], Field{Key: ErrorsFieldName, Val: bs})
+		break
+	}
+	return layers
+}
+
+func Lookup[T any](err error, key string) (T, bool) {
+	fs := Fields(err)
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key != key {
+			continue
+		}
+		if val, ok := fs[i].Val.(T); ok {
+			return val, true
+		}
+		return convert[T](fs[i].Val)
+	}
+	var zero T
+	return zero, false
+}
+
+func convert[T any](v interface{}) (T, bool) {
+	var zero T
+	from, to := reflect.ValueOf(v), reflect.TypeOf(zero)
+	if !from.IsValid() || to == nil || family(from.Type()) == "" || family(from.Type()) != family(to) {
+		return zero, false
+	}
+	val := from.Convert(to)
+	if val.Convert(from.Type()).Interface() != v || negative(val) != negative(from) {
+		return zero, false
+	}
+	return val.Interface().(T), true
+}
+
+func family(t reflect.Type) string {
+	if t.PkgPath() != "" {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	default:
+		return ""
+	}
+}
+
+func negative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	default:
+		return false
+	}
+}
+
+func branches(errs []error) ([]Branch, bool) {
+	bs := make([]Branch, 0, len(errs))
+	found := false
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		fs := Fields(err)
+		if len(fs) > 0 {
+			found = true
+		}
+		bs = append(bs, Branch{err, fs})
+	}
+	return bs, found
+}
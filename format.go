@@ -0,0 +1,81 @@
+package rich
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			var sb strings.Builder
+			dump(&sb, e, "")
+			io.WriteString(s, strings.TrimSuffix(sb.String(), "\n"))
+			return
+		}
+		io.WriteString(s, plain(e))
+	case 's':
+		io.WriteString(s, plain(e))
+	case 'q':
+		fmt.Fprintf(s, "%q", plain(e))
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, e.Error())
+	}
+}
+
+func plain(e *Error) string {
+	return clean(e.err.Error(), e.err)
+}
+
+func clean(msg string, err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		r, ok := err.(*Error)
+		if ok && len(r.fs) > 0 {
+			msg = strings.Replace(msg, r.Error(), r.err.Error(), 1)
+		}
+		m, ok := err.(interface{ Unwrap() []error })
+		if ok {
+			for _, b := range m.Unwrap() {
+				msg = clean(msg, b)
+			}
+			break
+		}
+	}
+	return msg
+}
+
+func dump(sb *strings.Builder, err error, indent string) {
+	var last string
+	for ; err != nil; err = errors.Unwrap(err) {
+		if m, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, b := range m.Unwrap() {
+				fmt.Fprintf(sb, "%s  -\n", indent)
+				dump(sb, b, indent+"    ")
+			}
+			return
+		}
+		r, ok := err.(*Error)
+		if !ok {
+			if errors.Unwrap(err) == nil && err.Error() != last {
+				fmt.Fprintf(sb, "%s%s\n", indent, err)
+			}
+			continue
+		}
+		last = plain(r)
+		fmt.Fprintf(sb, "%s%s\n", indent, last)
+		for _, f := range r.fs {
+			if _, ok := f.Redact(ErrorRedaction); ok {
+				fmt.Fprintf(sb, "%s    %s\n", indent, f)
+			}
+		}
+		if len(r.stack) > 0 {
+			fmt.Fprintf(sb, "%s    stack:\n", indent)
+			for _, frame := range r.stack {
+				fmt.Fprintf(sb, "%s        %s\n%s            %s:%d\n", indent, frame.Function, indent, frame.File, frame.Line)
+			}
+		}
+	}
+}
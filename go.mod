@@ -1,6 +1,6 @@
 module github.com/awfm/rich
 
-go 1.13
+go 1.21
 
 require (
 	github.com/rs/zerolog v1.18.0
@@ -8,3 +8,8 @@ require (
 	go.uber.org/multierr v1.5.0
 	go.uber.org/zap v1.15.0
 )
+
+require (
+	go.uber.org/atomic v1.6.0 // indirect
+	golang.org/x/sys v0.0.0-20190422165155-953cdadca894 // indirect
+)
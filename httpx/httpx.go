@@ -0,0 +1,108 @@
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/awfm/rich"
+	rlogrus "github.com/awfm/rich/logrus"
+	rslog "github.com/awfm/rich/slog"
+	rzap "github.com/awfm/rich/zap"
+	rzerolog "github.com/awfm/rich/zerolog"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+var (
+	Message         = "request failed"
+	RequestIDHeader = "X-Request-ID"
+)
+
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+type Logger func(err error)
+
+func Zerolog(log func() *zerolog.Event) Logger {
+	return func(err error) {
+		rzerolog.Log(log).Err(err).Msg(Message)
+	}
+}
+
+func Zap(log *zap.Logger) Logger {
+	return func(err error) {
+		rzap.Log(log).Error(Message, zap.Error(err))
+	}
+}
+
+func Logrus(log logrus.FieldLogger) Logger {
+	return func(err error) {
+		rlogrus.Log(log).WithError(err).Error(Message)
+	}
+}
+
+func Slog(log *slog.Logger) Logger {
+	return func(err error) {
+		rslog.Log(log).Err(err).Error(Message)
+	}
+}
+
+func Handle(log Logger, h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &recorder{ResponseWriter: w}
+		err := h(rw, r)
+		if err == nil {
+			return
+		}
+		status := rich.HTTPStatus(err)
+		if rw.wrote {
+			status = rw.status
+		}
+		fs := []rich.Field{
+			{Key: "method", Val: r.Method},
+			{Key: "path", Val: r.URL.Path},
+			{Key: "remote_addr", Val: r.RemoteAddr},
+		}
+		if id := r.Header.Get(RequestIDHeader); id != "" {
+			fs = append(fs, rich.Field{Key: "request_id", Val: id})
+		}
+		fs = append(fs,
+			rich.Field{Key: "status", Val: status},
+			rich.Field{Key: "duration", Val: time.Since(start)},
+		)
+		log(rich.ErrorfCtx(r.Context(), "%w", err).With(fs...))
+		if !rw.wrote {
+			p := rich.ProblemOf(err)
+			p.Instance = r.URL.Path
+			rich.WriteProblem(w, p)
+		}
+	})
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (rw *recorder) WriteHeader(status int) {
+	if !rw.wrote {
+		rw.status = status
+		rw.wrote = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recorder) Write(b []byte) (int, error) {
+	if !rw.wrote {
+		rw.status = http.StatusOK
+		rw.wrote = true
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
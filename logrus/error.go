@@ -2,50 +2,264 @@ package rich
 
 import (
 	"context"
-	"errors"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net"
+	"sort"
+	"time"
 
+	"github.com/awfm/rich"
 	"github.com/sirupsen/logrus"
 )
 
 type Error struct {
-	err error
-	fs  fields
+	core *rich.Error
 }
 
 func Errorf(format string, a ...interface{}) *Error {
-	err := fmt.Errorf(format, a...)
-	w := errors.Unwrap(err)
-	var fs fields
-	var r *Error
-	if errors.As(w, &r) {
-		fs = r.fs
-	}
-	return &Error{
-		err: err,
-		fs:  fs,
+	return &Error{rich.Errorf(format, a...)}
+}
+
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) *Error {
+	return &Error{rich.ErrorfCtx(ctx, format, a...)}
+}
+
+func New(msg string) *Error {
+	return &Error{rich.New(msg)}
+}
+
+func Wrap(err error, msg string) error {
+	return WrapSkip(err, msg, 0)
+}
+
+func WrapSkip(err error, msg string, skip int) error {
+	r, ok := rich.WrapSkip(err, msg, skip).(*rich.Error)
+	if !ok {
+		return nil
 	}
+	return &Error{r}
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("%s (%s)", e.err, e.fs)
+	return e.core.Error()
+}
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	e.core.Format(s, verb)
 }
 
 func (e *Error) Unwrap() error {
-	return e.err
+	return e.core
 }
 
-func (e *Error) WithField(key string, value string) *Error {
-	e.fs = append(e.fs, ifaceField{key, value})
-	return e
+func (e *Error) WithStack() *Error {
+	return &Error{e.core.WithStack()}
+}
+
+func (e *Error) WithField(key string, value interface{}) *Error {
+	return &Error{e.core.Interface(key, value)}
 }
 
 func (e *Error) WithFields(fields logrus.Fields) *Error {
-	e.fs = append(e.fs, mapField{fields})
-	return e
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fs := make([]rich.Field, 0, len(keys))
+	for _, key := range keys {
+		fs = append(fs, rich.Field{Key: key, Val: fields[key]})
+	}
+	return &Error{e.core.With(fs...)}
+}
+
+func (e *Error) Bool(key string, val bool) *Error {
+	return &Error{e.core.Bool(key, val)}
+}
+
+func (e *Error) Int(key string, val int) *Error {
+	return &Error{e.core.Int(key, val)}
+}
+
+func (e *Error) Int8(key string, val int8) *Error {
+	return &Error{e.core.Int8(key, val)}
+}
+
+func (e *Error) Int16(key string, val int16) *Error {
+	return &Error{e.core.Int16(key, val)}
+}
+
+func (e *Error) Int32(key string, val int32) *Error {
+	return &Error{e.core.Int32(key, val)}
+}
+
+func (e *Error) Int64(key string, val int64) *Error {
+	return &Error{e.core.Int64(key, val)}
+}
+
+func (e *Error) Uint(key string, val uint) *Error {
+	return &Error{e.core.Uint(key, val)}
+}
+
+func (e *Error) Uint8(key string, val uint8) *Error {
+	return &Error{e.core.Uint8(key, val)}
+}
+
+func (e *Error) Uint16(key string, val uint16) *Error {
+	return &Error{e.core.Uint16(key, val)}
+}
+
+func (e *Error) Uint32(key string, val uint32) *Error {
+	return &Error{e.core.Uint32(key, val)}
+}
+
+func (e *Error) Uint64(key string, val uint64) *Error {
+	return &Error{e.core.Uint64(key, val)}
+}
+
+func (e *Error) Float32(key string, val float32) *Error {
+	return &Error{e.core.Float32(key, val)}
+}
+
+func (e *Error) Float64(key string, val float64) *Error {
+	return &Error{e.core.Float64(key, val)}
+}
+
+func (e *Error) Str(key string, val string) *Error {
+	return &Error{e.core.Str(key, val)}
+}
+
+func (e *Error) AnErr(key string, val error) *Error {
+	return &Error{e.core.AnErr(key, val)}
+}
+
+func (e *Error) Dur(key string, val time.Duration) *Error {
+	return &Error{e.core.Dur(key, val)}
+}
+
+func (e *Error) Time(key string, val time.Time) *Error {
+	return &Error{e.core.Time(key, val)}
+}
+
+func (e *Error) Bools(key string, val []bool) *Error {
+	return &Error{e.core.Bools(key, val)}
+}
+
+func (e *Error) Ints(key string, val []int) *Error {
+	return &Error{e.core.Ints(key, val)}
+}
+
+func (e *Error) Ints8(key string, val []int8) *Error {
+	return &Error{e.core.Ints8(key, val)}
+}
+
+func (e *Error) Ints16(key string, val []int16) *Error {
+	return &Error{e.core.Ints16(key, val)}
+}
+
+func (e *Error) Ints32(key string, val []int32) *Error {
+	return &Error{e.core.Ints32(key, val)}
+}
+
+func (e *Error) Ints64(key string, val []int64) *Error {
+	return &Error{e.core.Ints64(key, val)}
+}
+
+func (e *Error) Uints(key string, val []uint) *Error {
+	return &Error{e.core.Uints(key, val)}
+}
+
+func (e *Error) Uints8(key string, val []uint8) *Error {
+	return &Error{e.core.Uints8(key, val)}
+}
+
+func (e *Error) Uints16(key string, val []uint16) *Error {
+	return &Error{e.core.Uints16(key, val)}
+}
+
+func (e *Error) Uints32(key string, val []uint32) *Error {
+	return &Error{e.core.Uints32(key, val)}
+}
+
+func (e *Error) Uints64(key string, val []uint64) *Error {
+	return &Error{e.core.Uints64(key, val)}
+}
+
+func (e *Error) Floats32(key string, val []float32) *Error {
+	return &Error{e.core.Floats32(key, val)}
+}
+
+func (e *Error) Floats64(key string, val []float64) *Error {
+	return &Error{e.core.Floats64(key, val)}
+}
+
+func (e *Error) Strs(key string, val []string) *Error {
+	return &Error{e.core.Strs(key, val)}
+}
+
+func (e *Error) Errs(key string, val []error) *Error {
+	return &Error{e.core.Errs(key, val)}
+}
+
+func (e *Error) Durs(key string, val []time.Duration) *Error {
+	return &Error{e.core.Durs(key, val)}
+}
+
+func (e *Error) Times(key string, val []time.Time) *Error {
+	return &Error{e.core.Times(key, val)}
+}
+
+func (e *Error) Bytes(key string, val []byte) *Error {
+	return &Error{e.core.Str(key, string(val))}
+}
+
+func (e *Error) Hex(key string, val []byte) *Error {
+	return &Error{e.core.Str(key, hex.EncodeToString(val))}
+}
+
+func (e *Error) RawJSON(key string, val []byte) *Error {
+	return &Error{e.core.Interface(key, json.RawMessage(val))}
+}
+
+func (e *Error) IPAddr(key string, val net.IP) *Error {
+	return &Error{e.core.IPAddr(key, val)}
+}
+
+func (e *Error) IPPrefix(key string, val net.IPNet) *Error {
+	return &Error{e.core.IPPrefix(key, val)}
+}
+
+func (e *Error) MACAddr(key string, val net.HardwareAddr) *Error {
+	return &Error{e.core.MACAddr(key, val)}
+}
+
+func (e *Error) Interface(key string, val interface{}) *Error {
+	return &Error{e.core.Interface(key, val)}
+}
+
+func (e *Error) WithCode(c rich.Code) *Error {
+	return &Error{e.core.Code(c)}
+}
+
+func (e *Error) WithPublic(keys ...string) *Error {
+	return &Error{e.core.Public(keys...)}
+}
+
+func (e *Error) WithDetail(msg string) *Error {
+	return &Error{e.core.Detail(msg)}
+}
+
+func (e *Error) WithSecret(key string, value interface{}) *Error {
+	return &Error{e.core.Secret(key, value)}
 }
 
 func (e *Error) WithContext(ctx context.Context) *Error {
-	e.fs = append(e.fs, ctxField{ctx})
-	return e
+	return &Error{e.core.With(rich.ContextFields(ctx)...).Interface("context", ctx)}
+}
+
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = &Error{rich.Panic(v)}
+	}
 }
@@ -0,0 +1,55 @@
+package rich
+
+import (
+	"context"
+
+	"github.com/awfm/rich"
+	"github.com/sirupsen/logrus"
+)
+
+type Hook struct{}
+
+func (Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+	s := rich.StackTrace(err)
+	if len(rich.Fields(err)) == 0 && len(s) == 0 {
+		return nil
+	}
+	caller := data(entry.Data)
+	var fs []rich.Field
+	data := make(logrus.Fields, len(entry.Data))
+	if Nested {
+		data[rich.ChainFieldName] = chain(rich.Chain(err))
+		fs = append(contexts(rich.Fields(err)), rich.ContextFields(entry.Context)...)
+		fs = append(fs, caller...)
+	} else {
+		fs = rich.MergeContext(entry.Context, err, caller...)
+	}
+	for _, f := range fs {
+		f, ok := f.Redact(Redaction)
+		if !ok {
+			continue
+		}
+		switch val := f.Val.(type) {
+		case context.Context:
+			entry.Context = val
+		case []rich.Branch:
+			data[f.Key] = branches(val)
+		default:
+			data[f.Key] = plain(val)
+		}
+	}
+	if len(s) > 0 {
+		data[StackKey] = s.String()
+	}
+	data[logrus.ErrorKey] = opaque{rich.Strip(err)}
+	entry.Data = data
+	return nil
+}
@@ -2,83 +2,176 @@ package rich
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"net"
+	"sort"
 
+	"github.com/awfm/rich"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	StackKey  = "stack"
+	Nested    = false
+	Redaction = rich.Keep
+)
+
 type Logger struct {
-	log *logrus.Logger
+	log logrus.FieldLogger
+	ctx context.Context
 }
 
-func Log(log *logrus.Logger) *Logger {
-	return &Logger{log}
+func Log(log logrus.FieldLogger) *Logger {
+	return &Logger{log: log}
+}
+
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	return &Logger{l.log, ctx}
 }
 
 func (l *Logger) WithError(err error) *logrus.Entry {
-	r, ok := err.(*Error)
-	if !ok {
-		return l.log.WithError(err)
+	entry := l.log.WithError(seal(err))
+	if l.ctx != nil {
+		entry = entry.WithContext(l.ctx)
 	}
-	entry := l.log.WithError(r.err)
-	for _, f := range r.fs {
-		f.Log(entry)
+	caller := data(entry.Data)
+	fs := rich.MergeContext(entry.Context, err, caller...)
+	if Nested && len(rich.Fields(err)) > 0 {
+		entry = entry.WithField(rich.ChainFieldName, chain(rich.Chain(err)))
+		fs = append(contexts(rich.Fields(err)), rich.ContextFields(entry.Context)...)
+		fs = append(fs, caller...)
+	}
+	for _, f := range fs {
+		entry = field(entry, f)
+	}
+	if s := rich.StackTrace(err); len(s) > 0 {
+		entry = entry.WithField(StackKey, s.String())
 	}
 	return entry
 }
 
-type field interface {
-	fmt.Stringer
-	Log(en *logrus.Entry)
+func field(entry *logrus.Entry, f rich.Field) *logrus.Entry {
+	f, ok := f.Redact(Redaction)
+	if !ok {
+		return entry
+	}
+	switch val := f.Val.(type) {
+	case context.Context:
+		return entry.WithContext(val)
+	case []rich.Branch:
+		return entry.WithField(f.Key, branches(val))
+	default:
+		return entry.WithField(f.Key, plain(val))
+	}
 }
 
-type fields []field
-
-func (fs fields) String() string {
-	ss := make([]string, 0, len(fs))
-	for _, f := range fs {
-		ss = append(ss, f.String())
+func data(d logrus.Fields) []rich.Field {
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		if key != logrus.ErrorKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	fs := make([]rich.Field, 0, len(keys))
+	for _, key := range keys {
+		fs = append(fs, rich.Field{Key: key, Val: d[key]})
 	}
-	return strings.Join(ss, ", ")
+	return fs
 }
 
-type ifaceField struct {
-	key   string
-	value interface{}
+func plain(val interface{}) interface{} {
+	switch val := val.(type) {
+	case net.HardwareAddr:
+		return val.String()
+	case net.IPNet:
+		return val.String()
+	case *net.IPNet:
+		return val.String()
+	case []uint8:
+		ints := make([]uint16, 0, len(val))
+		for _, v := range val {
+			ints = append(ints, uint16(v))
+		}
+		return ints
+	case []error:
+		strs := make([]string, 0, len(val))
+		for _, v := range val {
+			strs = append(strs, v.Error())
+		}
+		return strs
+	default:
+		return val
+	}
 }
 
-func (f ifaceField) String() string {
-	return fmt.Sprintf("%s: %v", f.key, f.value)
+func branches(bs []rich.Branch) []logrus.Fields {
+	data := make([]logrus.Fields, 0, len(bs))
+	for _, b := range bs {
+		d := nested(b.Fields)
+		d["msg"] = rich.Strip(b.Err).Error()
+		data = append(data, d)
+	}
+	return data
 }
-func (f ifaceField) Log(en *logrus.Entry) {
-	en.WithField(f.key, f.value)
+
+func chain(ls []rich.Layer) []logrus.Fields {
+	data := make([]logrus.Fields, 0, len(ls))
+	for _, l := range ls {
+		data = append(data, logrus.Fields{
+			"msg":    rich.Strip(l.Err).Error(),
+			"fields": nested(l.Fields),
+		})
+	}
+	return data
 }
 
-type mapField struct {
-	fields logrus.Fields
+func nested(fs []rich.Field) logrus.Fields {
+	d := make(logrus.Fields, len(fs))
+	for _, f := range fs {
+		f, ok := f.Redact(Redaction)
+		if !ok {
+			continue
+		}
+		switch val := f.Val.(type) {
+		case context.Context:
+		case []rich.Branch:
+			d[f.Key] = branches(val)
+		case error:
+			d[f.Key] = val.Error()
+		default:
+			d[f.Key] = plain(val)
+		}
+	}
+	return d
 }
 
-func (f mapField) String() string {
-	ss := make([]string, 0, len(f.fields))
-	for key, value := range f.fields {
-		ss = append(ss, fmt.Sprintf("%s: %v", key, value))
+func contexts(fs []rich.Field) []rich.Field {
+	var ctxs []rich.Field
+	for _, f := range fs {
+		if _, ok := f.Val.(context.Context); ok {
+			ctxs = append(ctxs, f)
+		}
 	}
-	return strings.Join(ss, ", ")
+	return ctxs
 }
 
-func (f mapField) Log(en *logrus.Entry) {
-	en.WithFields(f.fields)
+func (l *Logger) Recover() {
+	if v := recover(); v != nil {
+		l.WithError(rich.Panic(v)).Error("panic")
+	}
 }
 
-type ctxField struct {
-	ctx context.Context
+func seal(err error) error {
+	if err == nil || len(rich.Fields(err)) == 0 && len(rich.StackTrace(err)) == 0 {
+		return err
+	}
+	return opaque{rich.Strip(err)}
 }
 
-func (f ctxField) String() string {
-	return fmt.Sprintf("context: %v", f.ctx)
+type opaque struct {
+	err error
 }
 
-func (f ctxField) Log(en *logrus.Entry) {
-	en.WithContext(f.ctx)
+func (o opaque) Error() string {
+	return o.err.Error()
 }
@@ -0,0 +1,141 @@
+package rich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awfm/rich"
+	"github.com/sirupsen/logrus"
+)
+
+func logged(t *testing.T, hook bool, log func(l *logrus.Logger)) map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	if hook {
+		l.AddHook(Hook{})
+	}
+	log(l)
+	var data map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("could not decode %q: %v", buf.String(), err)
+	}
+	return data
+}
+
+func TestFieldTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		key  string
+		want interface{}
+	}{
+		{"Bool", New("e").Bool("k", true), "k", true},
+		{"Int", New("e").Int("k", -1), "k", -1.0},
+		{"Int8", New("e").Int8("k", -8), "k", -8.0},
+		{"Int16", New("e").Int16("k", -16), "k", -16.0},
+		{"Int32", New("e").Int32("k", -32), "k", -32.0},
+		{"Int64", New("e").Int64("k", -64), "k", -64.0},
+		{"Uint", New("e").Uint("k", 1), "k", 1.0},
+		{"Uint8", New("e").Uint8("k", 8), "k", 8.0},
+		{"Uint16", New("e").Uint16("k", 16), "k", 16.0},
+		{"Uint32", New("e").Uint32("k", 32), "k", 32.0},
+		{"Uint64", New("e").Uint64("k", 64), "k", 64.0},
+		{"Float32", New("e").Float32("k", 1.5), "k", 1.5},
+		{"Float64", New("e").Float64("k", 2.5), "k", 2.5},
+		{"Str", New("e").Str("k", "v"), "k", "v"},
+		{"AnErr", New("e").AnErr("k", errors.New("inner")), "k", "inner"},
+		{"Dur", New("e").Dur("k", time.Second), "k", 1e9},
+		{"Time", New("e").Time("k", time.Unix(0, 0).UTC()), "k", "1970-01-01T00:00:00Z"},
+		{"Bools", New("e").Bools("k", []bool{true, false}), "k", []interface{}{true, false}},
+		{"Ints", New("e").Ints("k", []int{1, 2}), "k", []interface{}{1.0, 2.0}},
+		{"Ints8", New("e").Ints8("k", []int8{1}), "k", []interface{}{1.0}},
+		{"Ints16", New("e").Ints16("k", []int16{1}), "k", []interface{}{1.0}},
+		{"Ints32", New("e").Ints32("k", []int32{1}), "k", []interface{}{1.0}},
+		{"Ints64", New("e").Ints64("k", []int64{1}), "k", []interface{}{1.0}},
+		{"Uints", New("e").Uints("k", []uint{1}), "k", []interface{}{1.0}},
+		{"Uints8", New("e").Uints8("k", []uint8{1, 2}), "k", []interface{}{1.0, 2.0}},
+		{"Uints16", New("e").Uints16("k", []uint16{1}), "k", []interface{}{1.0}},
+		{"Uints32", New("e").Uints32("k", []uint32{1}), "k", []interface{}{1.0}},
+		{"Uints64", New("e").Uints64("k", []uint64{1}), "k", []interface{}{1.0}},
+		{"Floats32", New("e").Floats32("k", []float32{0.5}), "k", []interface{}{0.5}},
+		{"Floats64", New("e").Floats64("k", []float64{0.5}), "k", []interface{}{0.5}},
+		{"Strs", New("e").Strs("k", []string{"a"}), "k", []interface{}{"a"}},
+		{"Errs", New("e").Errs("k", []error{errors.New("a")}), "k", []interface{}{"a"}},
+		{"Durs", New("e").Durs("k", []time.Duration{time.Second}), "k", []interface{}{1e9}},
+		{"Times", New("e").Times("k", []time.Time{time.Unix(0, 0).UTC()}), "k", []interface{}{"1970-01-01T00:00:00Z"}},
+		{"Bytes", New("e").Bytes("k", []byte("ab")), "k", "ab"},
+		{"Hex", New("e").Hex("k", []byte{0xff}), "k", "ff"},
+		{"RawJSON", New("e").RawJSON("k", []byte(`{"a":1}`)), "k", map[string]interface{}{"a": 1.0}},
+		{"IPAddr", New("e").IPAddr("k", net.IP{1, 2, 3, 4}), "k", "1.2.3.4"},
+		{"IPPrefix", New("e").IPPrefix("k", net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}), "k", "10.0.0.0/8"},
+		{"MACAddr", New("e").MACAddr("k", net.HardwareAddr{1, 2, 3, 4, 5, 6}), "k", "01:02:03:04:05:06"},
+		{"Interface", New("e").Interface("k", map[string]int{"a": 1}), "k", map[string]interface{}{"a": 1.0}},
+		{"WithField", New("e").WithField("k", 3), "k", 3.0},
+		{"WithFields", New("e").WithFields(logrus.Fields{"k": false}), "k", false},
+		{"WithCode", New("e").WithCode(rich.NotFound), rich.CodeFieldName, "not_found"},
+		{"WithContext", New("e").WithContext(rich.ContextWith(context.Background(), rich.Field{Key: "k", Val: "ctx"})), "k", "ctx"},
+	}
+	for _, tt := range tests {
+		for _, hook := range []bool{false, true} {
+			name := tt.name + "/WithError"
+			if hook {
+				name = tt.name + "/Hook"
+			}
+			t.Run(name, func(t *testing.T) {
+				data := logged(t, hook, func(l *logrus.Logger) {
+					if hook {
+						l.WithError(tt.err).Error("msg")
+					} else {
+						Log(l).WithError(tt.err).Error("msg")
+					}
+				})
+				got, ok := data[tt.key]
+				if !ok {
+					t.Fatalf("%s missing from %v", tt.key, data)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("%s = %#v, want %#v", tt.key, got, tt.want)
+				}
+				if data[logrus.ErrorKey] != "e" {
+					t.Fatalf("%s = %#v, want %q", logrus.ErrorKey, data[logrus.ErrorKey], "e")
+				}
+			})
+		}
+	}
+}
+
+func TestEntryData(t *testing.T) {
+	data := logged(t, false, func(l *logrus.Logger) {
+		entry := l.WithField("service", "api")
+		Log(entry).WithError(New("e").Int("n", 1)).Error("msg")
+	})
+	want := map[string]interface{}{"service": "api", "n": 1.0, "error": "e"}
+	for key, val := range want {
+		if !reflect.DeepEqual(data[key], val) {
+			t.Fatalf("%s = %#v, want %#v", key, data[key], val)
+		}
+	}
+}
+
+func TestHookSkipsExpandedErrors(t *testing.T) {
+	defer func(p rich.Policy) { rich.Collisions = p }(rich.Collisions)
+	for _, p := range []rich.Policy{rich.LastWins, rich.Collect, rich.PrefixDepth} {
+		rich.Collisions = p
+		data := logged(t, true, func(l *logrus.Logger) {
+			Log(l).WithError(fmt.Errorf("x: %w", New("e").WithField("a", "1"))).Error("msg")
+		})
+		if data["a"] != "1" || len(data) != 5 {
+			t.Fatalf("policy %d: got %v", p, data)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package rich
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var (
+	ProblemBaseURI  = ""
+	DetailFieldName = "detail"
+)
+
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func (e *Error) Public(keys ...string) *Error {
+	public := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		public[key] = true
+	}
+	fs := make(fields, len(e.fs))
+	for i, f := range e.fs {
+		f.Public = f.Public || public[f.Key]
+		fs[i] = f
+	}
+	return &Error{
+		err:   e.err,
+		fs:    fs,
+		stack: e.stack,
+	}
+}
+
+func (e *Error) Detail(msg string) *Error {
+	return e.with(Field{Key: DetailFieldName, Val: msg, Public: true})
+}
+
+func ProblemOf(err error) *Problem {
+	status := HTTPStatus(err)
+	code := CodeOf(err)
+	p := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Extensions: make(map[string]interface{}),
+	}
+	if p.Title == "" {
+		p.Title = string(code)
+	}
+	if ProblemBaseURI != "" && code != "" && code != Unknown {
+		p.Type = ProblemBaseURI + string(code)
+	}
+	for _, f := range Merge(err) {
+		if !f.Public {
+			continue
+		}
+		f, ok := f.Redact(ErrorRedaction)
+		if !ok {
+			continue
+		}
+		switch f.Key {
+		case DetailFieldName:
+			if detail, ok := f.Val.(string); ok {
+				p.Detail = detail
+			}
+		case "type", "title", "status", "instance":
+		default:
+			p.Extensions[f.Key] = f.Val
+		}
+	}
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, val := range p.Extensions {
+		doc[key] = val
+	}
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+func WriteProblem(w http.ResponseWriter, p *Problem) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,27 @@
+package rich
+
+import "fmt"
+
+var (
+	PanicValueFieldName = "panic_value"
+	PanicTypeFieldName  = "panic_type"
+)
+
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = Panic(v)
+	}
+}
+
+func Panic(v interface{}) *Error {
+	var e *Error
+	if err, ok := v.(error); ok {
+		e = Errorf("panic: %w", err)
+	} else {
+		e = Errorf("panic: %v", v)
+	}
+	e.stack = callers(0)
+	return e.Code(Internal).
+		Str(PanicValueFieldName, fmt.Sprint(v)).
+		Str(PanicTypeFieldName, fmt.Sprintf("%T", v))
+}
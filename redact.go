@@ -0,0 +1,43 @@
+package rich
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+type Redaction int
+
+const (
+	Keep Redaction = iota
+	Mask
+	Hash
+	Drop
+)
+
+var (
+	SecretKeys     *regexp.Regexp
+	ErrorRedaction = Mask
+	MaskText       = "***"
+)
+
+func (f Field) Sensitive() bool {
+	return f.Secret || SecretKeys != nil && SecretKeys.MatchString(f.Key)
+}
+
+func (f Field) Redact(r Redaction) (Field, bool) {
+	if r == Keep || !f.Sensitive() {
+		return f, true
+	}
+	switch r {
+	case Mask:
+		f.Val = MaskText
+	case Hash:
+		sum := sha256.Sum256([]byte(fmt.Sprint(f.Val)))
+		f.Val = "sha256:" + hex.EncodeToString(sum[:8])
+	default:
+		return f, false
+	}
+	return f, true
+}
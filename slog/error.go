@@ -0,0 +1,124 @@
+package rich
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/awfm/rich"
+)
+
+type Error struct {
+	core *rich.Error
+}
+
+func Errorf(format string, a ...interface{}) *Error {
+	return &Error{rich.Errorf(format, a...)}
+}
+
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) *Error {
+	return &Error{rich.ErrorfCtx(ctx, format, a...)}
+}
+
+func New(msg string) *Error {
+	return &Error{rich.New(msg)}
+}
+
+func Wrap(err error, msg string) error {
+	return WrapSkip(err, msg, 0)
+}
+
+func WrapSkip(err error, msg string, skip int) error {
+	r, ok := rich.WrapSkip(err, msg, skip).(*rich.Error)
+	if !ok {
+		return nil
+	}
+	return &Error{r}
+}
+
+func (e *Error) Error() string {
+	return e.core.Error()
+}
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	e.core.Format(s, verb)
+}
+
+func (e *Error) Unwrap() error {
+	return e.core
+}
+
+func (e *Error) WithStack() *Error {
+	return &Error{e.core.WithStack()}
+}
+
+func (e *Error) LogValue() slog.Value {
+	return value(e)
+}
+
+func (e *Error) With(attrs ...slog.Attr) *Error {
+	return &Error{e.core.With(neutral(attrs)...)}
+}
+
+func (e *Error) String(key string, val string) *Error {
+	return e.With(slog.String(key, val))
+}
+
+func (e *Error) Int(key string, val int) *Error {
+	return e.With(slog.Int(key, val))
+}
+
+func (e *Error) Int64(key string, val int64) *Error {
+	return e.With(slog.Int64(key, val))
+}
+
+func (e *Error) Uint64(key string, val uint64) *Error {
+	return e.With(slog.Uint64(key, val))
+}
+
+func (e *Error) Float64(key string, val float64) *Error {
+	return e.With(slog.Float64(key, val))
+}
+
+func (e *Error) Bool(key string, val bool) *Error {
+	return e.With(slog.Bool(key, val))
+}
+
+func (e *Error) Time(key string, val time.Time) *Error {
+	return e.With(slog.Time(key, val))
+}
+
+func (e *Error) Duration(key string, val time.Duration) *Error {
+	return e.With(slog.Duration(key, val))
+}
+
+func (e *Error) Group(key string, args ...any) *Error {
+	return e.With(slog.Group(key, args...))
+}
+
+func (e *Error) Any(key string, val any) *Error {
+	return e.With(slog.Any(key, val))
+}
+
+func (e *Error) Code(c rich.Code) *Error {
+	return &Error{e.core.Code(c)}
+}
+
+func (e *Error) Public(keys ...string) *Error {
+	return &Error{e.core.Public(keys...)}
+}
+
+func (e *Error) Detail(msg string) *Error {
+	return &Error{e.core.Detail(msg)}
+}
+
+func (e *Error) Secret(key string, val any) *Error {
+	return &Error{e.core.Secret(key, val)}
+}
+
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = &Error{rich.Panic(v)}
+	}
+}
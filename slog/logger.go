@@ -0,0 +1,180 @@
+package rich
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/awfm/rich"
+)
+
+var (
+	StackKey  = "stack"
+	Nested    = false
+	Redaction = rich.Keep
+)
+
+type Logger struct {
+	log *slog.Logger
+	ctx context.Context
+}
+
+func Log(log *slog.Logger) *Logger {
+	return &Logger{log: log}
+}
+
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	return &Logger{l.log, ctx}
+}
+
+func (l *Logger) Err(err error) *slog.Logger {
+	return l.log.With(expand(l.ctx, nil, nil, named{"error", err})...)
+}
+
+func (l *Logger) With(args ...any) *slog.Logger {
+	var caller []rich.Field
+	var rest []any
+	var errs []named
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case slog.Attr:
+			err, ok := arg.Value.Any().(error)
+			if kind := arg.Value.Kind(); !ok || kind != slog.KindAny && kind != slog.KindLogValuer {
+				caller = append(caller, neutral([]slog.Attr{arg})...)
+				continue
+			}
+			errs = append(errs, named{arg.Key, err})
+		case string:
+			if i == len(args)-1 {
+				rest = append(rest, arg)
+				continue
+			}
+			err, ok := args[i+1].(error)
+			if !ok {
+				caller = append(caller, neutral([]slog.Attr{slog.Any(arg, args[i+1])})...)
+				i++
+				continue
+			}
+			errs = append(errs, named{arg, err})
+			i++
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return l.log.With(expand(l.ctx, caller, rest, errs...)...)
+}
+
+type named struct {
+	key string
+	err error
+}
+
+func expand(ctx context.Context, caller []rich.Field, rest []any, errs ...named) []any {
+	var sources [][]rich.Field
+	var chains []any
+	for _, n := range errs {
+		if Nested && len(rich.Fields(n.err)) > 0 {
+			chains = append(chains, slog.Attr{Key: rich.ChainFieldName, Value: chain(rich.Chain(n.err))})
+			continue
+		}
+		sources = append(sources, rich.Sources(n.err)...)
+	}
+	var fs []rich.Field
+	if Nested {
+		fs = append(rich.ContextFields(ctx), caller...)
+	} else {
+		fs = rich.ResolveContext(ctx, append(sources, caller)...)
+	}
+	args := make([]any, 0, len(chains)+len(fs)+2*len(errs)+len(rest))
+	args = append(args, chains...)
+	for _, f := range fs {
+		args = append(args, attr(f))
+	}
+	for _, n := range errs {
+		if s := rich.StackTrace(n.err); len(s) > 0 {
+			args = append(args, slog.String(StackKey, s.String()))
+		}
+		args = append(args, slog.Any(n.key, rich.Strip(n.err)))
+	}
+	return append(args, rest...)
+}
+
+func value(err error) slog.Value {
+	fs := rich.Merge(err)
+	attrs := make([]slog.Attr, 0, len(fs)+1)
+	attrs = append(attrs, slog.String("msg", rich.Strip(err).Error()))
+	if Nested && len(fs) > 0 {
+		attrs = append(attrs, slog.Attr{Key: rich.ChainFieldName, Value: chain(rich.Chain(err))})
+		fs = nil
+	}
+	for _, f := range fs {
+		attrs = append(attrs, attr(f))
+	}
+	if s := rich.StackTrace(err); len(s) > 0 {
+		attrs = append(attrs, slog.String(StackKey, s.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func attr(f rich.Field) slog.Attr {
+	f, ok := f.Redact(Redaction)
+	if !ok {
+		return slog.Attr{}
+	}
+	switch val := f.Val.(type) {
+	case slog.Attr:
+		val.Key = f.Key
+		return val
+	case []rich.Branch:
+		return slog.Attr{Key: f.Key, Value: branches(val)}
+	default:
+		return slog.Any(f.Key, val)
+	}
+}
+
+func branches(bs []rich.Branch) slog.Value {
+	groups := make([]slog.Attr, 0, len(bs))
+	for i, b := range bs {
+		attrs := make([]slog.Attr, 0, len(b.Fields)+1)
+		attrs = append(attrs, slog.String("msg", rich.Strip(b.Err).Error()))
+		for _, f := range b.Fields {
+			attrs = append(attrs, attr(f))
+		}
+		groups = append(groups, slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(attrs...)})
+	}
+	return slog.GroupValue(groups...)
+}
+
+func chain(ls []rich.Layer) slog.Value {
+	groups := make([]slog.Attr, 0, len(ls))
+	for i, l := range ls {
+		attrs := make([]slog.Attr, 0, len(l.Fields))
+		for _, f := range l.Fields {
+			attrs = append(attrs, attr(f))
+		}
+		groups = append(groups, slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(
+			slog.String("msg", rich.Strip(l.Err).Error()),
+			slog.Attr{Key: "fields", Value: slog.GroupValue(attrs...)},
+		)})
+	}
+	return slog.GroupValue(groups...)
+}
+
+func neutral(attrs []slog.Attr) []rich.Field {
+	fs := make([]rich.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		switch attr.Value.Kind() {
+		case slog.KindGroup, slog.KindLogValuer:
+			fs = append(fs, rich.Field{Key: attr.Key, Val: attr})
+		default:
+			fs = append(fs, rich.Field{Key: attr.Key, Val: attr.Value.Any()})
+		}
+	}
+	return fs
+}
+
+func (l *Logger) Recover() {
+	if v := recover(); v != nil {
+		l.Err(rich.Panic(v)).Error("panic")
+	}
+}
@@ -0,0 +1,40 @@
+package rich
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestWithErrorForms(t *testing.T) {
+	err := New("e").String("id", "x")
+	tests := []struct {
+		name string
+		args []any
+	}{
+		{"pair", []any{"error", err}},
+		{"Any", []any{slog.Any("error", err)}},
+	}
+	var want map[string]any
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		Log(slog.New(slog.NewJSONHandler(&buf, nil))).With(tt.args...).Info("m")
+		var got map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("%s: could not decode %q: %v", tt.name, buf.String(), err)
+		}
+		delete(got, "time")
+		if got["id"] != "x" {
+			t.Fatalf("%s: id = %v, want x in %v", tt.name, got["id"], got)
+		}
+		if want == nil {
+			want = got
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rich
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+var CaptureStack = false
+
+var pkg = reflect.TypeOf(Error{}).PkgPath()
+
+type Stack []runtime.Frame
+
+func (s Stack) String() string {
+	ss := make([]string, 0, len(s))
+	for _, frame := range s {
+		ss = append(ss, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+	}
+	return strings.Join(ss, "\n")
+}
+
+func StackTrace(err error) Stack {
+	var s Stack
+	for ; err != nil; err = errors.Unwrap(err) {
+		r, ok := err.(*Error)
+		if ok && len(r.stack) > 0 {
+			s = r.stack
+		}
+	}
+	return s
+}
+
+func callers(skip int) Stack {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var s Stack
+	for {
+		frame, more := frames.Next()
+		switch {
+		case len(s) == 0 && internal(frame.Function):
+		case skip > 0:
+			skip--
+		default:
+			s = append(s, frame)
+		}
+		if !more {
+			break
+		}
+	}
+	return s
+}
+
+func internal(function string) bool {
+	return strings.HasPrefix(function, pkg+".") || strings.HasPrefix(function, pkg+"/")
+}
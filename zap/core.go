@@ -0,0 +1,64 @@
+package rich
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+type wrapped struct {
+	zapcore.Core
+	scope
+}
+
+func WrapCore(core zapcore.Core) zapcore.Core {
+	return &wrapped{Core: core}
+}
+
+func (w *wrapped) With(fields []zapcore.Field) zapcore.Core {
+	expanded, s := w.with(fields)
+	return &wrapped{w.Core.With(expanded), s}
+}
+
+func (w *wrapped) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := w.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(ent, &checked{w, inner})
+}
+
+func (w *wrapped) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return w.Core.Write(ent, w.expand(fields))
+}
+
+type checked struct {
+	*wrapped
+	inner *zapcore.CheckedEntry
+}
+
+func (c *checked) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	sink := &sink{}
+	c.inner.ErrorOutput = sink
+	c.inner.Write(c.expand(fields)...)
+	return sink.err
+}
+
+type sink struct {
+	err error
+}
+
+func (s *sink) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	if i := strings.Index(msg, "write error: "); i >= 0 {
+		msg = msg[i+len("write error: "):]
+	}
+	s.err = multierr.Append(s.err, errors.New(msg))
+	return len(p), nil
+}
+
+func (s *sink) Sync() error {
+	return nil
+}
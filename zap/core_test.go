@@ -0,0 +1,94 @@
+package rich
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWrapCoreTee(t *testing.T) {
+	all, allLogs := observer.New(zapcore.DebugLevel)
+	errs, errLogs := observer.New(zapcore.ErrorLevel)
+	log := zap.New(WrapCore(zapcore.NewTee(all, errs)))
+	err := New("e").With(zap.Int("n", 1))
+
+	log.Info("info", zap.Error(err))
+	log.Error("error", zap.Error(err))
+
+	if got := allLogs.Len(); got != 2 {
+		t.Fatalf("debug core got %d entries, want 2", got)
+	}
+	if got := errLogs.Len(); got != 1 {
+		t.Fatalf("error core got %d entries, want 1", got)
+	}
+	entry := errLogs.All()[0]
+	if entry.Message != "error" || entry.ContextMap()["n"] != int64(1) {
+		t.Fatalf("error core got %+v", entry)
+	}
+}
+
+func TestWrapCoreSampler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(WrapCore(zapcore.NewSampler(core, time.Minute, 1, 100)))
+	for i := 0; i < 3; i++ {
+		log.Info("same", zap.Error(New("e").With(zap.Int("n", i))))
+	}
+	if got := logs.Len(); got != 1 {
+		t.Fatalf("got %d entries, want 1", got)
+	}
+	if n := logs.All()[0].ContextMap()["n"]; n != int64(0) {
+		t.Fatalf("n = %v, want 0", n)
+	}
+}
+
+func TestLoggerCheck(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := Log(zap.New(core))
+	if ce := log.Check(zapcore.DebugLevel, "debug"); ce != nil {
+		t.Fatal("debug entry should not be checked")
+	}
+	log.Check(zapcore.InfoLevel, "info").Write(zap.Error(New("e").With(zap.String("k", "v"))))
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].ContextMap()["k"] != "v" {
+		t.Fatalf("got %+v", entries)
+	}
+}
+
+type failing struct {
+	zapcore.LevelEnabler
+}
+
+func (f failing) With([]zapcore.Field) zapcore.Core {
+	return f
+}
+
+func (f failing) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if f.Enabled(ent.Level) {
+		return ce.AddCore(ent, f)
+	}
+	return ce
+}
+
+func (failing) Write(zapcore.Entry, []zapcore.Field) error {
+	return errors.New("disk full")
+}
+
+func (failing) Sync() error {
+	return nil
+}
+
+func TestWrapCoreWriteError(t *testing.T) {
+	var buf bytes.Buffer
+	log := zap.New(WrapCore(failing{zapcore.DebugLevel}), zap.ErrorOutput(zapcore.AddSync(&buf)))
+	log.Info("m")
+	out := buf.String()
+	if !strings.Contains(out, "write error: disk full") || strings.Count(out, "write error") != 1 {
+		t.Fatalf("error output = %q", out)
+	}
+}
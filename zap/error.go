@@ -1,73 +1,123 @@
 package rich
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/awfm/rich"
 	"go.uber.org/zap"
 )
 
 type Error struct {
-	err    error
-	fields []zap.Field
+	core *rich.Error
 }
 
 func Errorf(format string, a ...interface{}) *Error {
-	err := fmt.Errorf(format, a...)
-	w := errors.Unwrap(err)
-	var fields []zap.Field
-	var e *Error
-	if errors.As(w, &e) {
-		fields = e.fields
-	}
-	var s *Sugared
-	if errors.As(w, &s) {
-		fields = sweeten(s.args)
-	}
-	return &Error{
-		err:    err,
-		fields: fields,
+	return &Error{rich.Errorf(format, a...)}
+}
+
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) *Error {
+	return &Error{rich.ErrorfCtx(ctx, format, a...)}
+}
+
+func New(msg string) *Error {
+	return &Error{rich.New(msg)}
+}
+
+func Wrap(err error, msg string) error {
+	return WrapSkip(err, msg, 0)
+}
+
+func WrapSkip(err error, msg string, skip int) error {
+	r, ok := rich.WrapSkip(err, msg, skip).(*rich.Error)
+	if !ok {
+		return nil
 	}
+	return &Error{r}
 }
 
 func (e *Error) Error() string {
-	ss := make([]string, 0, len(e.fields))
-	for _, field := range e.fields {
-		ss = append(ss, fmt.Sprintf("%s: %v", field.Key, field.Interface))
-	}
-	msg := strings.Join(ss, ", ")
-	return fmt.Sprintf("%s (%s)", e.err, msg)
+	return e.core.Error()
+}
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	e.core.Format(s, verb)
+}
+
+func (e *Error) Unwrap() error {
+	return e.core
+}
+
+func (e *Error) WithStack() *Error {
+	return &Error{e.core.WithStack()}
 }
 
 func (e *Error) With(fields ...zap.Field) *Error {
-	e.fields = append(e.fields, fields...)
-	return e
+	return &Error{e.core.With(neutral(fields)...)}
+}
+
+func (e *Error) Code(c rich.Code) *Error {
+	return &Error{e.core.Code(c)}
+}
+
+func (e *Error) Public(keys ...string) *Error {
+	return &Error{e.core.Public(keys...)}
+}
+
+func (e *Error) Detail(msg string) *Error {
+	return &Error{e.core.Detail(msg)}
+}
+
+func (e *Error) Secret(fields ...zap.Field) *Error {
+	return &Error{e.core.With(secret(neutral(fields))...)}
 }
 
 func (e *Error) Sugar() *Sugared {
-	return &Sugared{
-		err:  e.err,
-		args: flatten(e.fields),
-	}
+	return &Sugared{e.core}
 }
 
 type Sugared struct {
-	err  error
-	args []interface{}
+	core *rich.Error
 }
 
 func (s *Sugared) Error() string {
-	fields := sweeten(s.args)
-	ss := make([]string, 0, len(fields))
-	for _, field := range fields {
-		ss = append(ss, fmt.Sprintf("%s: %v", field.Key, field.Interface))
-	}
-	msg := strings.Join(ss, ", ")
-	return fmt.Sprintf("%s (%s)", s.err, msg)
+	return s.core.Error()
+}
+
+func (s *Sugared) Format(st fmt.State, verb rune) {
+	s.core.Format(st, verb)
+}
+
+func (s *Sugared) Unwrap() error {
+	return s.core
+}
+
+func (s *Sugared) WithStack() *Sugared {
+	return &Sugared{s.core.WithStack()}
 }
 
 func (s *Sugared) With(args ...interface{}) *Sugared {
-	s.args = append(s.args, args...)
-	return s
+	return &Sugared{s.core.With(neutral(sweeten(args))...)}
+}
+
+func (s *Sugared) Code(c rich.Code) *Sugared {
+	return &Sugared{s.core.Code(c)}
+}
+
+func (s *Sugared) Public(keys ...string) *Sugared {
+	return &Sugared{s.core.Public(keys...)}
+}
+
+func (s *Sugared) Detail(msg string) *Sugared {
+	return &Sugared{s.core.Detail(msg)}
+}
+
+func (s *Sugared) Secret(args ...interface{}) *Sugared {
+	return &Sugared{s.core.With(secret(neutral(sweeten(args)))...)}
+}
+
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = &Error{rich.Panic(v)}
+	}
 }
@@ -1,47 +1,216 @@
 package rich
 
 import (
+	"context"
+
+	"github.com/awfm/rich"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+var (
+	Nested    = false
+	Redaction = rich.Keep
+)
+
 type Logger struct {
 	log *zap.Logger
+	scope
 }
 
 func Log(log *zap.Logger) *Logger {
-	return &Logger{log}
+	return &Logger{log: log.WithOptions(zap.AddCallerSkip(1))}
+}
+
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	return &Logger{l.log, scope{ctx, l.prior}}
+}
+
+func (l *Logger) Sugar() *SugaredLogger {
+	return &SugaredLogger{l.log.Sugar(), l.scope}
+}
+
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{l.log.Named(name), l.scope}
+}
+
+func (l *Logger) WithOptions(opts ...zap.Option) *Logger {
+	return &Logger{l.log.WithOptions(opts...), l.scope}
+}
+
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	expanded, s := l.with(fields)
+	return &Logger{l.log.With(expanded...), s}
+}
+
+func (l *Logger) Check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	return l.log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &wrapped{core, l.scope}
+	})).Check(lvl, msg)
+}
+
+func (l *Logger) Debug(msg string, fields ...zap.Field) {
+	l.log.Debug(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Info(msg string, fields ...zap.Field) {
+	l.log.Info(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Warn(msg string, fields ...zap.Field) {
+	l.log.Warn(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Error(msg string, fields ...zap.Field) {
+	l.log.Error(msg, l.expand(fields)...)
 }
 
-func (l *Logger) With(fields ...zap.Field) *zap.Logger {
-	if len(fields) > 0 && fields[0].Type == zapcore.ErrorType {
-		fields = append(fs(fields[0].Interface.(error)), fields[1:]...)
+func (l *Logger) DPanic(msg string, fields ...zap.Field) {
+	l.log.DPanic(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Panic(msg string, fields ...zap.Field) {
+	l.log.Panic(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Fatal(msg string, fields ...zap.Field) {
+	l.log.Fatal(msg, l.expand(fields)...)
+}
+
+func (l *Logger) Sync() error {
+	return l.log.Sync()
+}
+
+func (l *Logger) Core() zapcore.Core {
+	return l.log.Core()
+}
+
+func (l *Logger) Recover() {
+	if v := recover(); v != nil {
+		l.log.Error("panic", l.expand([]zap.Field{zap.Error(rich.Panic(v))})...)
 	}
-	return l.log.With(fields...)
 }
 
 type SugaredLogger struct {
 	log *zap.SugaredLogger
+	scope
 }
 
 func Sugar(log *zap.SugaredLogger) *SugaredLogger {
-	return &SugaredLogger{log}
+	return &SugaredLogger{log: log.Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar()}
 }
 
-func (s *SugaredLogger) With(args ...interface{}) *zap.SugaredLogger {
-	if len(args) == 0 {
-		return s.log.With(args...)
-	}
-	field, isField := args[0].(zap.Field)
-	if isField && field.Type == zapcore.ErrorType {
-		args = append(as(field.Interface.(error)), args[1:]...)
-	}
-	key, isKey := args[0].(string)
-	if isKey && key == "error" && len(args) > 1 {
-		err, isErr := args[1].(error)
-		if isErr {
-			args = append(as(err), args[2:]...)
-		}
+func (s *SugaredLogger) Ctx(ctx context.Context) *SugaredLogger {
+	return &SugaredLogger{s.log, scope{ctx, s.prior}}
+}
+
+func (s *SugaredLogger) Desugar() *Logger {
+	return &Logger{s.log.Desugar(), s.scope}
+}
+
+func (s *SugaredLogger) Named(name string) *SugaredLogger {
+	return &SugaredLogger{s.log.Named(name), s.scope}
+}
+
+func (s *SugaredLogger) With(args ...interface{}) *SugaredLogger {
+	expanded, sc := s.with(sweeten(args))
+	return &SugaredLogger{s.log.With(loosen(expanded)...), sc}
+}
+
+func (s *SugaredLogger) Debug(args ...interface{}) {
+	s.log.Debug(args...)
+}
+
+func (s *SugaredLogger) Info(args ...interface{}) {
+	s.log.Info(args...)
+}
+
+func (s *SugaredLogger) Warn(args ...interface{}) {
+	s.log.Warn(args...)
+}
+
+func (s *SugaredLogger) Error(args ...interface{}) {
+	s.log.Error(args...)
+}
+
+func (s *SugaredLogger) DPanic(args ...interface{}) {
+	s.log.DPanic(args...)
+}
+
+func (s *SugaredLogger) Panic(args ...interface{}) {
+	s.log.Panic(args...)
+}
+
+func (s *SugaredLogger) Fatal(args ...interface{}) {
+	s.log.Fatal(args...)
+}
+
+func (s *SugaredLogger) Debugf(template string, args ...interface{}) {
+	s.log.Debugf(template, args...)
+}
+
+func (s *SugaredLogger) Infof(template string, args ...interface{}) {
+	s.log.Infof(template, args...)
+}
+
+func (s *SugaredLogger) Warnf(template string, args ...interface{}) {
+	s.log.Warnf(template, args...)
+}
+
+func (s *SugaredLogger) Errorf(template string, args ...interface{}) {
+	s.log.Errorf(template, args...)
+}
+
+func (s *SugaredLogger) DPanicf(template string, args ...interface{}) {
+	s.log.DPanicf(template, args...)
+}
+
+func (s *SugaredLogger) Panicf(template string, args ...interface{}) {
+	s.log.Panicf(template, args...)
+}
+
+func (s *SugaredLogger) Fatalf(template string, args ...interface{}) {
+	s.log.Fatalf(template, args...)
+}
+
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	s.log.Debugw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{}) {
+	s.log.Infow(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	s.log.Warnw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	s.log.Errorw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) DPanicw(msg string, keysAndValues ...interface{}) {
+	s.log.DPanicw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Panicw(msg string, keysAndValues ...interface{}) {
+	s.log.Panicw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...interface{}) {
+	s.log.Fatalw(msg, s.sweeten(keysAndValues)...)
+}
+
+func (s *SugaredLogger) Sync() error {
+	return s.log.Sync()
+}
+
+func (s *SugaredLogger) Recover() {
+	if v := recover(); v != nil {
+		s.log.Errorw("panic", s.sweeten([]interface{}{"error", rich.Panic(v)})...)
 	}
-	return s.log.With(args...)
+}
+
+func (s *SugaredLogger) sweeten(args []interface{}) []interface{} {
+	return loosen(s.expand(sweeten(args)))
 }
@@ -0,0 +1,48 @@
+package rich
+
+import (
+	"testing"
+
+	"github.com/awfm/rich"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithCollisions(t *testing.T) {
+	defer func(p rich.Policy) { rich.Collisions = p }(rich.Collisions)
+	tests := []struct {
+		policy rich.Policy
+		want   map[string]interface{}
+	}{
+		{rich.LastWins, map[string]interface{}{"id": "prior"}},
+		{rich.FirstWins, map[string]interface{}{"id": "prior", "1.id": "err"}},
+		{rich.PrefixDepth, map[string]interface{}{"id": "prior", "1.id": "err"}},
+		{rich.Collect, map[string]interface{}{"id": "prior", "1.id": "err"}},
+	}
+	for _, tt := range tests {
+		rich.Collisions = tt.policy
+		core, logs := observer.New(zapcore.DebugLevel)
+		Log(zap.New(core)).
+			With(zap.String("id", "prior")).
+			Error("m", zap.Error(New("e").With(zap.String("id", "err"))))
+		got := make(map[string]interface{})
+		for _, f := range logs.All()[0].Context {
+			if f.Key == "error" {
+				continue
+			}
+			if _, ok := got[f.Key]; ok {
+				t.Fatalf("policy %d: duplicate key %s", tt.policy, f.Key)
+			}
+			got[f.Key] = f.String
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("policy %d: got %v, want %v", tt.policy, got, tt.want)
+		}
+		for key, val := range tt.want {
+			if got[key] != val {
+				t.Fatalf("policy %d: %s = %v, want %v", tt.policy, key, got[key], val)
+			}
+		}
+	}
+}
@@ -1,45 +1,190 @@
 package rich
 
 import (
-	"errors"
+	"context"
+	"strconv"
 
+	"github.com/awfm/rich"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-func fs(err error) []zap.Field {
-	var r *Error
-	if errors.As(err, &r) {
-		return append([]zap.Field{zap.Error(r.err)}, r.fields...)
+type scope struct {
+	ctx   context.Context
+	prior []rich.Field
+}
+
+func (s scope) expand(fields []zap.Field) []zap.Field {
+	expanded, _ := s.resolve(fields)
+	return expanded
+}
+
+func (s scope) with(fields []zap.Field) ([]zap.Field, scope) {
+	expanded, fs := s.resolve(fields)
+	return expanded, scope{prior: append(s.prior[:len(s.prior):len(s.prior)], fs...)}
+}
+
+func (s scope) resolve(fields []zap.Field) ([]zap.Field, []rich.Field) {
+	var caller []rich.Field
+	var errs []zap.Field
+	for _, field := range fields {
+		_, ok := field.Interface.(error)
+		if field.Type == zapcore.ErrorType && ok {
+			errs = append(errs, field)
+			continue
+		}
+		caller = append(caller, rich.Field{Key: field.Key, Val: field})
 	}
-	var s *Sugared
-	if errors.As(err, &s) {
-		return append([]zap.Field{zap.Error(s.err)}, sweeten(s.args)...)
+	if len(errs) == 0 && len(rich.ContextFields(s.ctx)) == 0 {
+		return fields, caller
 	}
-	return []zap.Field{zap.Error(err)}
+	written := make(map[string]bool, len(s.prior))
+	for _, f := range s.prior {
+		written[f.Key] = true
+	}
+	for _, f := range caller {
+		delete(written, f.Key)
+	}
+	expanded := make([]zap.Field, 0, len(fields))
+	var sources [][]rich.Field
+	for _, field := range errs {
+		err := field.Interface.(error)
+		expanded = append(expanded, zap.NamedError(field.Key, seal(err)))
+		if st := rich.StackTrace(err); len(st) > 0 {
+			expanded = append(expanded, zap.String("stacktrace", st.String()))
+		}
+		if Nested && len(rich.Fields(err)) > 0 {
+			expanded = append(expanded, zap.Array(rich.ChainFieldName, chain(rich.Chain(err))))
+			continue
+		}
+		sources = append(sources, rich.Sources(err)...)
+	}
+	if rich.Collisions == rich.FirstWins || rich.Collisions == rich.Collect {
+		sources = prefix(sources, written)
+	}
+	caller = append(s.prior[:len(s.prior):len(s.prior)], caller...)
+	var fs []rich.Field
+	for _, f := range rich.ResolveContext(s.ctx, append(sources, caller)...) {
+		if written[f.Key] {
+			continue
+		}
+		expanded = append(expanded, native(f))
+		fs = append(fs, f)
+	}
+	return expanded, fs
 }
 
-func as(err error) []interface{} {
-	var r *Error
-	if errors.As(err, &r) {
-		return append([]interface{}{zap.Error(r.err)}, flatten(r.fields)...)
+func prefix(sources [][]rich.Field, written map[string]bool) [][]rich.Field {
+	prefixed := make([][]rich.Field, 0, len(sources))
+	for i, source := range sources {
+		depth := strconv.Itoa(len(sources) - i)
+		fs := make([]rich.Field, 0, len(source))
+		for _, f := range source {
+			if written[f.Key] {
+				f.Key = depth + "." + f.Key
+			}
+			fs = append(fs, f)
+		}
+		prefixed = append(prefixed, fs)
 	}
-	var s *Sugared
-	if errors.As(err, &s) {
-		return append([]interface{}{zap.Error(s.err)}, s.args...)
+	return prefixed
+}
+
+func seal(err error) error {
+	if len(rich.Fields(err)) == 0 && len(rich.StackTrace(err)) == 0 {
+		return err
 	}
-	return []interface{}{zap.Error(s.err)}
+	return opaque{rich.Strip(err)}
+}
+
+type opaque struct {
+	err error
 }
 
-func flatten(fields []zap.Field) []interface{} {
-	args := make([]interface{}, 0, 2*len(fields))
+func (o opaque) Error() string {
+	return o.err.Error()
+}
+
+func loosen(fields []zap.Field) []interface{} {
+	args := make([]interface{}, 0, len(fields))
 	for _, field := range fields {
-		args = append(args, field.Key, field.Interface)
+		args = append(args, field)
 	}
 	return args
 }
 
+func native(f rich.Field) zap.Field {
+	f, ok := f.Redact(Redaction)
+	if !ok {
+		return zap.Skip()
+	}
+	switch val := f.Val.(type) {
+	case zap.Field:
+		val.Key = f.Key
+		return val
+	case []interface{}:
+		vals := make([]interface{}, 0, len(val))
+		for _, v := range val {
+			if field, ok := v.(zap.Field); ok {
+				v = neutral([]zap.Field{field})[0].Val
+			}
+			vals = append(vals, v)
+		}
+		return zap.Any(f.Key, vals)
+	case []rich.Branch:
+		return zap.Array(f.Key, branches(val))
+	default:
+		return zap.Any(f.Key, val)
+	}
+}
+
+type branches []rich.Branch
+
+func (bs branches) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	var err error
+	for _, b := range bs {
+		err = multierr.Append(err, enc.AppendObject(branch(b)))
+	}
+	return err
+}
+
+type branch rich.Branch
+
+func (b branch) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("msg", rich.Strip(b.Err).Error())
+	for _, f := range b.Fields {
+		native(f).AddTo(enc)
+	}
+	return nil
+}
+
+func neutral(fields []zap.Field) []rich.Field {
+	fs := make([]rich.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.Type == zapcore.ErrorType {
+			fs = append(fs, rich.Field{Key: field.Key, Val: field.Interface})
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		val, ok := enc.Fields[field.Key]
+		if !ok || len(enc.Fields) != 1 {
+			fs = append(fs, rich.Field{Key: field.Key, Val: field})
+			continue
+		}
+		fs = append(fs, rich.Field{Key: field.Key, Val: val})
+	}
+	return fs
+}
+
+func secret(fs []rich.Field) []rich.Field {
+	for i := range fs {
+		fs[i].Secret = true
+	}
+	return fs
+}
+
 func sweeten(args []interface{}) []zap.Field {
 	if len(args) == 0 {
 		return nil
@@ -74,6 +219,28 @@ func sweeten(args []interface{}) []zap.Field {
 	return fields
 }
 
+type chain []rich.Layer
+
+func (c chain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	var err error
+	for _, l := range c {
+		err = multierr.Append(err, enc.AppendObject(layer(l)))
+	}
+	return err
+}
+
+type layer rich.Layer
+
+func (l layer) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("msg", rich.Strip(l.Err).Error())
+	return enc.AddObject("fields", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, f := range l.Fields {
+			native(f).AddTo(enc)
+		}
+		return nil
+	}))
+}
+
 type invalidPair struct {
 	position   int
 	key, value interface{}
@@ -1,280 +1,278 @@
 package rich
 
 import (
-	"errors"
+	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net"
 	"runtime"
+	"sort"
 	"time"
 
+	"github.com/awfm/rich"
 	"github.com/rs/zerolog"
 )
 
 type Error struct {
-	err error
-	fs  fields
+	core *rich.Error
 }
 
 func Errorf(format string, a ...interface{}) *Error {
-	err := fmt.Errorf(format, a...)
-	w := errors.Unwrap(err)
-	var fs fields
-	var r *Error
-	if errors.As(w, &r) {
-		fs = r.fs
-	}
-	return &Error{
-		err: err,
-		fs:  fs,
+	return &Error{rich.Errorf(format, a...)}
+}
+
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) *Error {
+	return &Error{rich.ErrorfCtx(ctx, format, a...)}
+}
+
+func New(msg string) *Error {
+	return &Error{rich.New(msg)}
+}
+
+func Wrap(err error, msg string) error {
+	return WrapSkip(err, msg, 0)
+}
+
+func WrapSkip(err error, msg string, skip int) error {
+	r, ok := rich.WrapSkip(err, msg, skip).(*rich.Error)
+	if !ok {
+		return nil
 	}
+	return &Error{r}
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("%s (%s)", e.err, e.fs)
+	return e.core.Error()
+}
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	e.core.Format(s, verb)
 }
 
 func (e *Error) Unwrap() error {
-	return e.err
+	return e.core
+}
+
+func (e *Error) WithStack() *Error {
+	return &Error{e.core.WithStack()}
 }
 
 func (e *Error) Bool(key string, val bool) *Error {
-	e.fs = append(e.fs, boolField{key, val})
-	return e
+	return &Error{e.core.Bool(key, val)}
 }
 
 func (e *Error) Int(key string, val int) *Error {
-	e.fs = append(e.fs, intField{key, val})
-	return e
+	return &Error{e.core.Int(key, val)}
 }
 
 func (e *Error) Int8(key string, val int8) *Error {
-	e.fs = append(e.fs, int8Field{key, val})
-	return e
+	return &Error{e.core.Int8(key, val)}
 }
 
 func (e *Error) Int16(key string, val int16) *Error {
-	e.fs = append(e.fs, int16Field{key, val})
-	return e
+	return &Error{e.core.Int16(key, val)}
 }
 
 func (e *Error) Int32(key string, val int32) *Error {
-	e.fs = append(e.fs, int32Field{key, val})
-	return e
+	return &Error{e.core.Int32(key, val)}
 }
 
 func (e *Error) Int64(key string, val int64) *Error {
-	e.fs = append(e.fs, int64Field{key, val})
-	return e
+	return &Error{e.core.Int64(key, val)}
 }
 
 func (e *Error) Uint(key string, val uint) *Error {
-	e.fs = append(e.fs, uintField{key, val})
-	return e
+	return &Error{e.core.Uint(key, val)}
 }
 
 func (e *Error) Uint8(key string, val uint8) *Error {
-	e.fs = append(e.fs, uint8Field{key, val})
-	return e
+	return &Error{e.core.Uint8(key, val)}
 }
 
 func (e *Error) Uint16(key string, val uint16) *Error {
-	e.fs = append(e.fs, uint16Field{key, val})
-	return e
+	return &Error{e.core.Uint16(key, val)}
 }
 
 func (e *Error) Uint32(key string, val uint32) *Error {
-	e.fs = append(e.fs, uint32Field{key, val})
-	return e
+	return &Error{e.core.Uint32(key, val)}
 }
 
 func (e *Error) Uint64(key string, val uint64) *Error {
-	e.fs = append(e.fs, uint64Field{key, val})
-	return e
+	return &Error{e.core.Uint64(key, val)}
 }
 
 func (e *Error) Float32(key string, val float32) *Error {
-	e.fs = append(e.fs, float32Field{key, val})
-	return e
+	return &Error{e.core.Float32(key, val)}
 }
 
 func (e *Error) Float64(key string, val float64) *Error {
-	e.fs = append(e.fs, float64Field{key, val})
-	return e
+	return &Error{e.core.Float64(key, val)}
 }
 
 func (e *Error) Str(key string, val string) *Error {
-	e.fs = append(e.fs, strField{key, val})
-	return e
+	return &Error{e.core.Str(key, val)}
 }
 
 func (e *Error) AnErr(key string, val error) *Error {
-	e.fs = append(e.fs, errField{key, val})
-	return e
+	return &Error{e.core.AnErr(key, val)}
 }
 
 func (e *Error) Dur(key string, val time.Duration) *Error {
-	e.fs = append(e.fs, durField{key, val})
-	return e
+	return &Error{e.core.Dur(key, val)}
 }
 
 func (e *Error) Time(key string, val time.Time) *Error {
-	e.fs = append(e.fs, timeField{key, val})
-	return e
+	return &Error{e.core.Time(key, val)}
 }
 
 func (e *Error) Bools(key string, val []bool) *Error {
-	e.fs = append(e.fs, boolsField{key, val})
-	return e
+	return &Error{e.core.Bools(key, val)}
 }
 
 func (e *Error) Ints(key string, val []int) *Error {
-	e.fs = append(e.fs, intsField{key, val})
-	return e
+	return &Error{e.core.Ints(key, val)}
 }
 
 func (e *Error) Ints8(key string, val []int8) *Error {
-	e.fs = append(e.fs, ints8Field{key, val})
-	return e
+	return &Error{e.core.Ints8(key, val)}
 }
 
 func (e *Error) Ints16(key string, val []int16) *Error {
-	e.fs = append(e.fs, ints16Field{key, val})
-	return e
+	return &Error{e.core.Ints16(key, val)}
 }
 
 func (e *Error) Ints32(key string, val []int32) *Error {
-	e.fs = append(e.fs, ints32Field{key, val})
-	return e
+	return &Error{e.core.Ints32(key, val)}
 }
 
 func (e *Error) Ints64(key string, val []int64) *Error {
-	e.fs = append(e.fs, ints64Field{key, val})
-	return e
+	return &Error{e.core.Ints64(key, val)}
 }
 
 func (e *Error) Uints(key string, val []uint) *Error {
-	e.fs = append(e.fs, uintsField{key, val})
-	return e
+	return &Error{e.core.Uints(key, val)}
 }
 
 func (e *Error) Uints8(key string, val []uint8) *Error {
-	e.fs = append(e.fs, uints8Field{key, val})
-	return e
+	return &Error{e.core.Uints8(key, val)}
 }
 
 func (e *Error) Uints16(key string, val []uint16) *Error {
-	e.fs = append(e.fs, uints16Field{key, val})
-	return e
+	return &Error{e.core.Uints16(key, val)}
 }
 
 func (e *Error) Uints32(key string, val []uint32) *Error {
-	e.fs = append(e.fs, uints32Field{key, val})
-	return e
+	return &Error{e.core.Uints32(key, val)}
 }
 
 func (e *Error) Uints64(key string, val []uint64) *Error {
-	e.fs = append(e.fs, uints64Field{key, val})
-	return e
+	return &Error{e.core.Uints64(key, val)}
 }
 
 func (e *Error) Floats32(key string, val []float32) *Error {
-	e.fs = append(e.fs, floats32Field{key, val})
-	return e
+	return &Error{e.core.Floats32(key, val)}
 }
 
 func (e *Error) Floats64(key string, val []float64) *Error {
-	e.fs = append(e.fs, floats64Field{key, val})
-	return e
+	return &Error{e.core.Floats64(key, val)}
 }
 
 func (e *Error) Strs(key string, val []string) *Error {
-	e.fs = append(e.fs, strsField{key, val})
-	return e
+	return &Error{e.core.Strs(key, val)}
 }
 
 func (e *Error) Errs(key string, val []error) *Error {
-	e.fs = append(e.fs, errsField{key, val})
-	return e
+	return &Error{e.core.Errs(key, val)}
 }
 
 func (e *Error) Durs(key string, val []time.Duration) *Error {
-	e.fs = append(e.fs, dursField{key, val})
-	return e
+	return &Error{e.core.Durs(key, val)}
 }
 
 func (e *Error) Times(key string, val []time.Time) *Error {
-	e.fs = append(e.fs, timesField{key, val})
-	return e
+	return &Error{e.core.Times(key, val)}
 }
 
 func (e *Error) Bytes(key string, val []byte) *Error {
-	e.fs = append(e.fs, bytesField{key, val})
-	return e
+	return &Error{e.core.Str(key, string(val))}
 }
 
 func (e *Error) Hex(key string, val []byte) *Error {
-	e.fs = append(e.fs, hexField{key, val})
-	return e
+	return &Error{e.core.Str(key, hex.EncodeToString(val))}
 }
 
 func (e *Error) RawJSON(key string, val []byte) *Error {
-	e.fs = append(e.fs, jsonField{key, val})
-	return e
+	return &Error{e.core.Interface(key, json.RawMessage(val))}
 }
 
 func (e *Error) IPAddr(key string, val net.IP) *Error {
-	e.fs = append(e.fs, ipField{key, val})
-	return e
+	return &Error{e.core.IPAddr(key, val)}
 }
 
 func (e *Error) IPPrefix(key string, val net.IPNet) *Error {
-	e.fs = append(e.fs, prefixField{key, val})
-	return e
+	return &Error{e.core.IPPrefix(key, val)}
 }
 
 func (e *Error) MACAddr(key string, val net.HardwareAddr) *Error {
-	e.fs = append(e.fs, macField{key, val})
-	return e
+	return &Error{e.core.MACAddr(key, val)}
 }
 
 func (e *Error) Interface(key string, val interface{}) *Error {
-	e.fs = append(e.fs, ifaceField{key, val})
-	return e
+	return &Error{e.core.Interface(key, val)}
+}
+
+func (e *Error) Code(c rich.Code) *Error {
+	return &Error{e.core.Code(c)}
+}
+
+func (e *Error) Public(keys ...string) *Error {
+	return &Error{e.core.Public(keys...)}
+}
+
+func (e *Error) Detail(msg string) *Error {
+	return &Error{e.core.Detail(msg)}
+}
+
+func (e *Error) Secret(key string, val interface{}) *Error {
+	return &Error{e.core.Secret(key, val)}
 }
 
 func (e *Error) Timestamp() *Error {
-	e.fs = append(e.fs, tsField{zerolog.TimestampFunc()})
-	return e
+	return &Error{e.core.Time(zerolog.TimestampFieldName, zerolog.TimestampFunc())}
 }
 
 func (e *Error) TimeDiff(key string, val1 time.Time, val2 time.Time) *Error {
-	e.fs = append(e.fs, diffField{key, val1, val2})
-	return e
+	return &Error{e.core.Dur(key, val1.Sub(val2))}
 }
 
 func (e *Error) Fields(val map[string]interface{}) *Error {
-	e.fs = append(e.fs, fieldsField{val})
-	return e
+	keys := make([]string, 0, len(val))
+	for key := range val {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fs := make([]rich.Field, 0, len(keys))
+	for _, key := range keys {
+		fs = append(fs, rich.Field{Key: key, Val: val[key]})
+	}
+	return &Error{e.core.With(fs...)}
 }
 
 func (e *Error) Array(key string, val zerolog.LogArrayMarshaler) *Error {
-	e.fs = append(e.fs, arrayField{key, val})
-	return e
+	return &Error{e.core.Interface(key, val)}
 }
 
 func (e *Error) Dict(key string, val *zerolog.Event) *Error {
-	e.fs = append(e.fs, dictField{key, val})
-	return e
+	return &Error{e.core.Interface(key, val)}
 }
 
 func (e *Error) Object(key string, val zerolog.LogObjectMarshaler) *Error {
-	e.fs = append(e.fs, objectField{key, val})
-	return e
+	return &Error{e.core.Interface(key, val)}
 }
 
 func (e *Error) EmbedObject(val zerolog.LogObjectMarshaler) *Error {
-	e.fs = append(e.fs, embedField{val})
-	return e
+	return &Error{e.core.Interface("", embed{val})}
 }
 
 func (e *Error) Caller(val ...int) *Error {
@@ -286,6 +284,11 @@ func (e *Error) Caller(val ...int) *Error {
 	if !ok {
 		return e
 	}
-	e.fs = append(e.fs, callerField{file, line})
-	return e
+	return &Error{e.core.Str(zerolog.CallerFieldName, zerolog.CallerMarshalFunc(file, line))}
+}
+
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = &Error{rich.Panic(v)}
+	}
 }
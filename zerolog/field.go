@@ -0,0 +1,13 @@
+package rich
+
+import (
+	"github.com/awfm/rich"
+)
+
+func Fields(err error) []rich.Field {
+	return rich.Fields(err)
+}
+
+func Lookup[T any](err error, key string) (T, bool) {
+	return rich.Lookup[T](err, key)
+}